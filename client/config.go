@@ -0,0 +1,55 @@
+package client
+
+import (
+	"crypto/x509"
+
+	"github.com/mitch000001/go-hbci/transport"
+)
+
+// Config bundles client-side options for processing HBCI dialogs: the
+// two-step-TAN callback, the trusted certificate authorities used to
+// validate incoming RDH-10 (X.509) signatures, and the Transport used to
+// send messages to the bank.
+type Config struct {
+	// Tan holds the callback that supplies a TAN for a domain.TanChallenge.
+	// Nothing in Config or HTTPExchanger invokes it; dialog.TanOrder takes
+	// the callback it invokes as its own argument.
+	Tan TanCallback
+	// RootCertificates holds the certificate authorities incoming RDH-10
+	// signatures must chain to; a signer whose certificate does not validate
+	// against RootCertificates is rejected
+	RootCertificates *x509.CertPool
+	// Transport sends messages to the bank
+	Transport transport.Transport
+}
+
+// Option configures a Config
+type Option func(*Config)
+
+// NewConfig creates a Config with its defaults applied, then opts on top.
+func NewConfig(opts ...Option) *Config {
+	c := &Config{
+		Transport: transport.NewHTTPTransport(10, 1),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// WithTransport overrides the Transport used to send messages to the bank.
+func WithTransport(t transport.Transport) Option {
+	return func(c *Config) {
+		c.Transport = t
+	}
+}
+
+// WithRateLimit limits the default HTTP Transport to rps requests per
+// second, allowing bursts of up to burst requests. Some banks throttle
+// HBCI/FinTS endpoints to a few requests per second and answer further
+// requests with Rückmeldungscode 9050/9210.
+func WithRateLimit(rps float64, burst int) Option {
+	return func(c *Config) {
+		c.Transport = transport.NewHTTPTransport(rps, burst)
+	}
+}
@@ -0,0 +1,15 @@
+package client
+
+import (
+	"context"
+
+	"github.com/mitch000001/go-hbci/domain"
+)
+
+// TanCallback lets an application supply the TAN a user entered in response
+// to a challenge received for a two-step-TAN order (chipTAN, pushTAN,
+// photoTAN, ...). It is invoked once the dialog receives a domain.TanChallenge
+// and must return the TAN value to submit, or an error if none could be
+// obtained. ctx is cancelled if the surrounding dialog send is cancelled, so
+// a UI can abandon a pending prompt.
+type TanCallback func(ctx context.Context, challenge domain.TanChallenge) (string, error)
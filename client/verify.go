@@ -0,0 +1,30 @@
+package client
+
+import (
+	"fmt"
+
+	"github.com/mitch000001/go-hbci/crypto"
+	"github.com/mitch000001/go-hbci/segment"
+)
+
+// VerifyEncryptionHeader checks the certificate and signature carried by an
+// incoming EncryptionHeaderSegment (HNVSK) against c.RootCertificates,
+// rejecting envelopes signed by an untrusted party. signedMessage is the raw
+// bytes the accompanying signature header segment (HNSHK) was computed over
+// and signature its signature value.
+//
+// It is the caller of crypto.RDH10Signer.Verify: a Dialog must invoke it for
+// every incoming RDH-10 message before trusting the segments it carries.
+func (c *Config) VerifyEncryptionHeader(header *segment.EncryptionHeaderSegment, signedMessage, signature []byte) error {
+	if c.RootCertificates == nil {
+		return fmt.Errorf("client: no RootCertificates configured, refusing to trust HNVSK envelope")
+	}
+	if header == nil || header.Certificate == nil {
+		return fmt.Errorf("client: HNVSK envelope carries no certificate")
+	}
+	signer := &crypto.RDH10Signer{Roots: c.RootCertificates}
+	if err := signer.Verify(signedMessage, signature, header.Certificate.Content.Val()); err != nil {
+		return fmt.Errorf("client: error verifying HNVSK envelope: %v", err)
+	}
+	return nil
+}
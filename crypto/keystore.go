@@ -0,0 +1,41 @@
+package crypto
+
+import (
+	"crypto"
+	"crypto/x509"
+	"fmt"
+
+	"github.com/mitch000001/go-hbci/domain"
+)
+
+// KeyStore abstracts where signing and encryption keys are kept so that
+// private key material never has to enter process memory directly: callers
+// only ever see a crypto.Signer/crypto.Decrypter handle backed by the store.
+type KeyStore interface {
+	// LoadSigningKey returns a Signer for the signing key identified by name
+	LoadSigningKey(name domain.KeyName) (crypto.Signer, error)
+	// LoadEncryptionKey returns a Decrypter for the encryption key identified
+	// by name
+	LoadEncryptionKey(name domain.KeyName) (crypto.Decrypter, error)
+	// BumpKeyVersion activates the next KeyVersion for name so that
+	// subsequent LoadSigningKey/LoadEncryptionKey calls for name use it, as
+	// used by the HBCI key-change (HKSAK) flow. It returns an error if the
+	// next KeyVersion has not been provisioned into the store out of band,
+	// or if the store has no way to activate a key version at all.
+	BumpKeyVersion(name domain.KeyName) error
+}
+
+// NewRDH10SignerFromKeyStore builds a RDH10Signer whose Key is loaded from
+// store, so the RDH-10 private key material for name never has to leave
+// store.
+func NewRDH10SignerFromKeyStore(store KeyStore, name domain.KeyName, certificate *x509.Certificate, roots *x509.CertPool) (*RDH10Signer, error) {
+	signer, err := store.LoadSigningKey(name)
+	if err != nil {
+		return nil, fmt.Errorf("crypto: error loading signing key %+v: %v", name, err)
+	}
+	return &RDH10Signer{
+		Key:         signer,
+		Certificate: certificate,
+		Roots:       roots,
+	}, nil
+}
@@ -0,0 +1,96 @@
+package crypto
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sync"
+
+	"github.com/mitch000001/go-hbci/domain"
+)
+
+// PEMKeyStore loads signing and encryption keys from a directory containing
+// one PEM-encoded RSA private key file per KeyName, named
+// "<bankID>.<userID>.<keyType><keyNumber>.<keyVersion>.pem", e.g.
+// "12345.user1.S1.1.pem".
+type PEMKeyStore struct {
+	Dir string
+
+	mu     sync.Mutex
+	active map[string]int // keyID -> active KeyVersion, once bumped past the file's own KeyVersion
+}
+
+// NewPEMKeyStore creates a PEMKeyStore reading keys from dir
+func NewPEMKeyStore(dir string) *PEMKeyStore {
+	return &PEMKeyStore{Dir: dir, active: make(map[string]int)}
+}
+
+// LoadSigningKey loads the RSA private key for name from Dir
+func (k *PEMKeyStore) LoadSigningKey(name domain.KeyName) (crypto.Signer, error) {
+	return k.loadKey(name)
+}
+
+// LoadEncryptionKey loads the RSA private key for name from Dir
+func (k *PEMKeyStore) LoadEncryptionKey(name domain.KeyName) (crypto.Decrypter, error) {
+	return k.loadKey(name)
+}
+
+// keyID identifies a key irrespective of its KeyVersion, so a bumped version
+// can be tracked and later resolved for any KeyName the caller passes in.
+func keyID(name domain.KeyName) string {
+	return fmt.Sprintf("%s.%s.%s%d", name.BankID.ID, name.UserID, name.KeyType, name.KeyNumber)
+}
+
+func (k *PEMKeyStore) path(name domain.KeyName) string {
+	return filepath.Join(k.Dir, fmt.Sprintf("%s.%d.pem", keyID(name), name.KeyVersion))
+}
+
+// resolve returns name with KeyVersion overridden by the active version if
+// BumpKeyVersion has moved it past what the caller asked for.
+func (k *PEMKeyStore) resolve(name domain.KeyName) domain.KeyName {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if version, ok := k.active[keyID(name)]; ok && version > name.KeyVersion {
+		name.KeyVersion = version
+	}
+	return name
+}
+
+func (k *PEMKeyStore) loadKey(name domain.KeyName) (*rsa.PrivateKey, error) {
+	name = k.resolve(name)
+	path := k.path(name)
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("crypto: error reading key file %q: %v", path, err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("crypto: no PEM block found in %q", path)
+	}
+	key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("crypto: error parsing private key in %q: %v", path, err)
+	}
+	return key, nil
+}
+
+// BumpKeyVersion activates the next KeyVersion for name: it requires the PEM
+// file for that version to already be provisioned in Dir, then makes
+// subsequent LoadSigningKey/LoadEncryptionKey calls for name (at any
+// KeyVersion at or below it) use it.
+func (k *PEMKeyStore) BumpKeyVersion(name domain.KeyName) error {
+	next := k.resolve(name)
+	next.KeyVersion++
+	path := k.path(next)
+	if _, err := ioutil.ReadFile(path); err != nil {
+		return fmt.Errorf("crypto: no key file provisioned for next KeyVersion %d: %v", next.KeyVersion, err)
+	}
+	k.mu.Lock()
+	k.active[keyID(name)] = next.KeyVersion
+	k.mu.Unlock()
+	return nil
+}
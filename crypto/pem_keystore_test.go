@@ -0,0 +1,100 @@
+package crypto
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/mitch000001/go-hbci/domain"
+)
+
+func writePEMKey(t *testing.T, dir, bankID, userID string, keyType domain.KeyType, keyNumber, keyVersion int) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatalf("error generating key: %v", err)
+	}
+	block := &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}
+	name := domain.KeyName{
+		BankID:     domain.BankID{ID: bankID},
+		UserID:     userID,
+		KeyType:    keyType,
+		KeyNumber:  keyNumber,
+		KeyVersion: keyVersion,
+	}
+	store := &PEMKeyStore{Dir: dir}
+	if err := ioutil.WriteFile(store.path(name), pem.EncodeToMemory(block), 0600); err != nil {
+		t.Fatalf("error writing key file: %v", err)
+	}
+	return key
+}
+
+func TestPEMKeyStore_LoadSigningKey(t *testing.T) {
+	dir, err := ioutil.TempDir("", "go-hbci-pem-keystore")
+	if err != nil {
+		t.Fatalf("error creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	name := domain.KeyName{BankID: domain.BankID{ID: "12345"}, UserID: "user1", KeyType: "S", KeyNumber: 1, KeyVersion: 1}
+	want := writePEMKey(t, dir, "12345", "user1", "S", 1, 1)
+
+	store := NewPEMKeyStore(dir)
+	got, err := store.LoadSigningKey(name)
+	if err != nil {
+		t.Fatalf("LoadSigningKey() returned error: %v", err)
+	}
+	signer, ok := got.(*rsa.PrivateKey)
+	if !ok {
+		t.Fatalf("expected *rsa.PrivateKey, got %T", got)
+	}
+	if signer.D.Cmp(want.D) != 0 {
+		t.Fatal("LoadSigningKey() returned a different key than was written")
+	}
+}
+
+func TestPEMKeyStore_BumpKeyVersion(t *testing.T) {
+	dir, err := ioutil.TempDir("", "go-hbci-pem-keystore")
+	if err != nil {
+		t.Fatalf("error creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	name := domain.KeyName{BankID: domain.BankID{ID: "12345"}, UserID: "user1", KeyType: "S", KeyNumber: 1, KeyVersion: 1}
+	writePEMKey(t, dir, "12345", "user1", "S", 1, 1)
+	v2 := writePEMKey(t, dir, "12345", "user1", "S", 1, 2)
+
+	store := NewPEMKeyStore(dir)
+	if err := store.BumpKeyVersion(name); err != nil {
+		t.Fatalf("BumpKeyVersion() returned error: %v", err)
+	}
+
+	got, err := store.LoadSigningKey(name)
+	if err != nil {
+		t.Fatalf("LoadSigningKey() returned error after bump: %v", err)
+	}
+	signer := got.(*rsa.PrivateKey)
+	if signer.D.Cmp(v2.D) != 0 {
+		t.Fatal("LoadSigningKey() did not use the bumped KeyVersion")
+	}
+}
+
+func TestPEMKeyStore_BumpKeyVersionRequiresProvisionedFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "go-hbci-pem-keystore")
+	if err != nil {
+		t.Fatalf("error creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	name := domain.KeyName{BankID: domain.BankID{ID: "12345"}, UserID: "user1", KeyType: "S", KeyNumber: 1, KeyVersion: 1}
+	writePEMKey(t, dir, "12345", "user1", "S", 1, 1)
+
+	store := NewPEMKeyStore(dir)
+	if err := store.BumpKeyVersion(name); err == nil {
+		t.Fatal("expected BumpKeyVersion to fail when the next KeyVersion has not been provisioned")
+	}
+}
@@ -0,0 +1,241 @@
+//go:build pkcs11
+
+package crypto
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"fmt"
+	"io"
+	"math/big"
+	"sync"
+
+	"github.com/miekg/pkcs11"
+	"github.com/mitch000001/go-hbci/domain"
+)
+
+// PKCS11KeyStore loads signing and encryption keys from a PKCS#11 compliant
+// HSM. Private key material never leaves the token: Sign and Decrypt are
+// performed by the HSM itself. Built only with the "pkcs11" build tag, since
+// it pulls in cgo bindings to the vendor-supplied PKCS#11 module.
+type PKCS11KeyStore struct {
+	Module string
+	Pin    string
+	Slot   uint
+
+	ctx *pkcs11.Ctx
+
+	mu      sync.Mutex
+	session pkcs11.SessionHandle
+	open    bool
+}
+
+// NewPKCS11KeyStore opens the PKCS#11 module at modulePath for slot. The
+// session itself is opened lazily on first use and kept open and logged in
+// across calls; call Close when the store is no longer needed.
+func NewPKCS11KeyStore(modulePath string, pin string, slot uint) (*PKCS11KeyStore, error) {
+	ctx := pkcs11.New(modulePath)
+	if ctx == nil {
+		return nil, fmt.Errorf("crypto: error loading PKCS#11 module %q", modulePath)
+	}
+	if err := ctx.Initialize(); err != nil {
+		return nil, fmt.Errorf("crypto: error initializing PKCS#11 module: %v", err)
+	}
+	return &PKCS11KeyStore{Module: modulePath, Pin: pin, Slot: slot, ctx: ctx}, nil
+}
+
+// Close logs out of and closes the underlying PKCS#11 session, then
+// finalizes the module. It must be called exactly once the store is no
+// longer used.
+func (k *PKCS11KeyStore) Close() error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.open {
+		if err := k.ctx.Logout(k.session); err != nil {
+			return fmt.Errorf("crypto: error logging out of PKCS#11 session: %v", err)
+		}
+		if err := k.ctx.CloseSession(k.session); err != nil {
+			return fmt.Errorf("crypto: error closing PKCS#11 session: %v", err)
+		}
+		k.open = false
+	}
+	k.ctx.Finalize()
+	return nil
+}
+
+// getSession returns the store's single open, logged-in PKCS#11 session,
+// opening and logging into it on first use. Every Load*Key call reuses it
+// instead of opening a new one, since HSMs typically cap the number of
+// concurrently open sessions.
+func (k *PKCS11KeyStore) getSession() (pkcs11.SessionHandle, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.open {
+		return k.session, nil
+	}
+	session, err := k.ctx.OpenSession(k.Slot, pkcs11.CKF_SERIAL_SESSION|pkcs11.CKF_RW_SESSION)
+	if err != nil {
+		return 0, fmt.Errorf("crypto: error opening PKCS#11 session: %v", err)
+	}
+	if err := k.ctx.Login(session, pkcs11.CKU_USER, k.Pin); err != nil {
+		return 0, fmt.Errorf("crypto: error logging into PKCS#11 token: %v", err)
+	}
+	k.session = session
+	k.open = true
+	return session, nil
+}
+
+// label derives the PKCS#11 object label a key is expected to be provisioned
+// under: "<userID>-<keyType><keyNumber>".
+func label(name domain.KeyName) string {
+	return fmt.Sprintf("%s-%s%d", name.UserID, name.KeyType, name.KeyNumber)
+}
+
+func (k *PKCS11KeyStore) findObject(session pkcs11.SessionHandle, name domain.KeyName, class uint) (pkcs11.ObjectHandle, error) {
+	template := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, class),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, label(name)),
+	}
+	if err := k.ctx.FindObjectsInit(session, template); err != nil {
+		return 0, fmt.Errorf("crypto: error initializing PKCS#11 object search: %v", err)
+	}
+	defer k.ctx.FindObjectsFinal(session)
+	objects, _, err := k.ctx.FindObjects(session, 1)
+	if err != nil {
+		return 0, fmt.Errorf("crypto: error searching PKCS#11 objects: %v", err)
+	}
+	if len(objects) == 0 {
+		return 0, fmt.Errorf("crypto: no PKCS#11 object labeled %q found", label(name))
+	}
+	return objects[0], nil
+}
+
+// publicKey reads the RSA public key matching name's private key from its
+// companion CKO_PUBLIC_KEY object, so pkcs11Key.Public() can return a real
+// key instead of leaving callers that need it (e.g. building the
+// EncryptionAlgorithm for an EncryptionHeaderSegment) with nil.
+func (k *PKCS11KeyStore) publicKey(session pkcs11.SessionHandle, name domain.KeyName) (*rsa.PublicKey, error) {
+	handle, err := k.findObject(session, name, pkcs11.CKO_PUBLIC_KEY)
+	if err != nil {
+		return nil, fmt.Errorf("crypto: error finding PKCS#11 public key: %v", err)
+	}
+	attrs, err := k.ctx.GetAttributeValue(session, handle, []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_MODULUS, nil),
+		pkcs11.NewAttribute(pkcs11.CKA_PUBLIC_EXPONENT, nil),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("crypto: error reading PKCS#11 public key attributes: %v", err)
+	}
+	modulus := new(big.Int).SetBytes(attrs[0].Value)
+	exponent := new(big.Int).SetBytes(attrs[1].Value)
+	return &rsa.PublicKey{N: modulus, E: int(exponent.Int64())}, nil
+}
+
+func (k *PKCS11KeyStore) key(name domain.KeyName) (*pkcs11Key, error) {
+	session, err := k.getSession()
+	if err != nil {
+		return nil, err
+	}
+	handle, err := k.findObject(session, name, pkcs11.CKO_PRIVATE_KEY)
+	if err != nil {
+		return nil, err
+	}
+	pub, err := k.publicKey(session, name)
+	if err != nil {
+		return nil, err
+	}
+	return &pkcs11Key{ctx: k.ctx, session: session, handle: handle, pub: pub}, nil
+}
+
+// LoadSigningKey returns a crypto.Signer backed by the HSM-resident private
+// key for name.
+func (k *PKCS11KeyStore) LoadSigningKey(name domain.KeyName) (crypto.Signer, error) {
+	return k.key(name)
+}
+
+// LoadEncryptionKey returns a crypto.Decrypter backed by the HSM-resident
+// private key for name.
+func (k *PKCS11KeyStore) LoadEncryptionKey(name domain.KeyName) (crypto.Decrypter, error) {
+	return k.key(name)
+}
+
+// BumpKeyVersion is not supported for HSM-backed keys: key material is
+// provisioned out of band by the HSM operator.
+func (k *PKCS11KeyStore) BumpKeyVersion(name domain.KeyName) error {
+	return fmt.Errorf("crypto: PKCS11KeyStore does not support key versioning, provision the new KeyVersion on the HSM instead")
+}
+
+// pkcs11Key adapts a PKCS#11 private key object to crypto.Signer and
+// crypto.Decrypter without ever reading out its private key material.
+type pkcs11Key struct {
+	ctx     *pkcs11.Ctx
+	session pkcs11.SessionHandle
+	handle  pkcs11.ObjectHandle
+	pub     *rsa.PublicKey
+}
+
+// Public returns the RSA public key matching this private key handle.
+func (k *pkcs11Key) Public() crypto.PublicKey {
+	return k.pub
+}
+
+// Sign selects the PKCS#11 mechanism matching opts: CKM_RSA_PKCS_PSS for
+// *rsa.PSSOptions (as used by RDH10Signer), CKM_RSA_PKCS otherwise. It
+// rejects unsupported option types rather than silently signing with the
+// wrong mechanism.
+func (k *pkcs11Key) Sign(rand io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	mechanism, err := k.mechanism(opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := k.ctx.SignInit(k.session, mechanism, k.handle); err != nil {
+		return nil, fmt.Errorf("crypto: error initializing PKCS#11 signing: %v", err)
+	}
+	return k.ctx.Sign(k.session, digest)
+}
+
+func (k *pkcs11Key) mechanism(opts crypto.SignerOpts) ([]*pkcs11.Mechanism, error) {
+	switch o := opts.(type) {
+	case *rsa.PSSOptions:
+		params := pkcs11.NewPSSParams(hashAlgForHash(o.Hash), mgfForHash(o.Hash), uint(o.Hash.Size()))
+		return []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_RSA_PKCS_PSS, params)}, nil
+	case crypto.Hash:
+		return []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_RSA_PKCS, nil)}, nil
+	default:
+		return nil, fmt.Errorf("crypto: pkcs11Key.Sign does not support SignerOpts of type %T", opts)
+	}
+}
+
+func hashAlgForHash(h crypto.Hash) uint {
+	switch h {
+	case crypto.SHA256:
+		return pkcs11.CKM_SHA256
+	case crypto.SHA384:
+		return pkcs11.CKM_SHA384
+	case crypto.SHA512:
+		return pkcs11.CKM_SHA512
+	default:
+		return pkcs11.CKM_SHA256
+	}
+}
+
+func mgfForHash(h crypto.Hash) uint {
+	switch h {
+	case crypto.SHA256:
+		return pkcs11.CKG_MGF1_SHA256
+	case crypto.SHA384:
+		return pkcs11.CKG_MGF1_SHA384
+	case crypto.SHA512:
+		return pkcs11.CKG_MGF1_SHA512
+	default:
+		return pkcs11.CKG_MGF1_SHA256
+	}
+}
+
+func (k *pkcs11Key) Decrypt(rand io.Reader, msg []byte, opts crypto.DecrypterOpts) ([]byte, error) {
+	mechanism := []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_RSA_PKCS, nil)}
+	if err := k.ctx.DecryptInit(k.session, mechanism, k.handle); err != nil {
+		return nil, fmt.Errorf("crypto: error initializing PKCS#11 decryption: %v", err)
+	}
+	return k.ctx.Decrypt(k.session, msg)
+}
@@ -0,0 +1,69 @@
+package crypto
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"fmt"
+	"io/ioutil"
+	"sync"
+
+	"github.com/mitch000001/go-hbci/domain"
+	"golang.org/x/crypto/pkcs12"
+)
+
+// PKCS12KeyStore loads a signing and an encryption key from a single
+// PKCS#12 encoded file, as commonly shipped by banks as a ".key" file. It
+// assumes the file holds exactly one RSA key pair, used for both signing
+// and encryption.
+type PKCS12KeyStore struct {
+	Path     string
+	Password string
+
+	mu        sync.Mutex
+	decrypted *rsa.PrivateKey
+}
+
+// NewPKCS12KeyStore creates a PKCS12KeyStore reading the PKCS#12 file at
+// path, decrypting it with password on first use.
+func NewPKCS12KeyStore(path, password string) *PKCS12KeyStore {
+	return &PKCS12KeyStore{Path: path, Password: password}
+}
+
+func (k *PKCS12KeyStore) key() (*rsa.PrivateKey, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.decrypted != nil {
+		return k.decrypted, nil
+	}
+	data, err := ioutil.ReadFile(k.Path)
+	if err != nil {
+		return nil, fmt.Errorf("crypto: error reading PKCS#12 file %q: %v", k.Path, err)
+	}
+	key, _, err := pkcs12.Decode(data, k.Password)
+	if err != nil {
+		return nil, fmt.Errorf("crypto: error decoding PKCS#12 file %q: %v", k.Path, err)
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("crypto: PKCS#12 file %q does not contain a RSA private key", k.Path)
+	}
+	k.decrypted = rsaKey
+	return rsaKey, nil
+}
+
+// LoadSigningKey returns the RSA key decoded from Path as a crypto.Signer
+func (k *PKCS12KeyStore) LoadSigningKey(name domain.KeyName) (crypto.Signer, error) {
+	return k.key()
+}
+
+// LoadEncryptionKey returns the RSA key decoded from Path as a
+// crypto.Decrypter
+func (k *PKCS12KeyStore) LoadEncryptionKey(name domain.KeyName) (crypto.Decrypter, error) {
+	return k.key()
+}
+
+// BumpKeyVersion is not supported: a PKCS#12 key change means provisioning a
+// new file out of band.
+func (k *PKCS12KeyStore) BumpKeyVersion(name domain.KeyName) error {
+	return fmt.Errorf("crypto: PKCS12KeyStore does not support key versioning, provision a new PKCS#12 file instead")
+}
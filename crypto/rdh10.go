@@ -0,0 +1,88 @@
+// Package crypto implements the cryptographic signing and encryption
+// profiles go-hbci uses to secure HBCI/FinTS messages.
+package crypto
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"fmt"
+
+	"github.com/mitch000001/go-hbci/element"
+)
+
+// RDH10Signer signs and verifies HBCI messages using the RDH-10 profile:
+// RSASSA-PSS over a SHA-256 message digest, with the signer identified by an
+// X.509 certificate.
+type RDH10Signer struct {
+	// Key signs outgoing messages. It is a crypto.Signer rather than a raw
+	// *rsa.PrivateKey so a KeyStore-backed key (PKCS#12, PEM directory, HSM)
+	// never has to expose its private key material to the caller.
+	Key crypto.Signer
+	// Certificate identifies Key towards the bank and is attached to every
+	// message signed with Sign
+	Certificate *x509.Certificate
+	// Roots holds the certificate authorities incoming signatures are
+	// validated against; a message signed by a certificate that does not
+	// chain to Roots is rejected by Verify
+	Roots *x509.CertPool
+}
+
+// SignatureAlgorithm returns the SignatureAlgorithmDataElement for the
+// RDH-10 profile
+func (s *RDH10Signer) SignatureAlgorithm() *element.SignatureAlgorithmDataElement {
+	return element.NewRDH10SignatureAlgorithm()
+}
+
+// HashAlgorithm returns the HashAlgorithmDataElement for the RDH-10 profile
+func (s *RDH10Signer) HashAlgorithm() *element.HashAlgorithmDataElement {
+	return element.NewRDH10HashAlgorithm()
+}
+
+// CertificateElement wraps Certificate into a CertificateDataElement of type
+// X.509 DER (CertificateType=3)
+func (s *RDH10Signer) CertificateElement() *element.CertificateDataElement {
+	return element.NewX509Certificate(s.Certificate.Raw)
+}
+
+// Sign signs message using RSASSA-PSS with a SHA-256 digest. The digest never
+// leaves this function unsigned: s.Key performs the actual RSA operation, so
+// a KeyStore-backed signer can keep the private key off-host.
+func (s *RDH10Signer) Sign(message []byte) ([]byte, error) {
+	digest := sha256.Sum256(message)
+	signature, err := s.Key.Sign(rand.Reader, digest[:], &rsa.PSSOptions{
+		SaltLength: rsa.PSSSaltLengthEqualsHash,
+		Hash:       crypto.SHA256,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("crypto: error signing message: %v", err)
+	}
+	return signature, nil
+}
+
+// Verify checks signature over message against the certificate carried in
+// certDER, rejecting certificates that do not chain to s.Roots.
+func (s *RDH10Signer) Verify(message, signature, certDER []byte) error {
+	cert, err := x509.ParseCertificate(certDER)
+	if err != nil {
+		return fmt.Errorf("crypto: error parsing certificate: %v", err)
+	}
+	if _, err := cert.Verify(x509.VerifyOptions{Roots: s.Roots}); err != nil {
+		return fmt.Errorf("crypto: error verifying certificate chain: %v", err)
+	}
+	publicKey, ok := cert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return fmt.Errorf("crypto: certificate does not carry a RSA public key")
+	}
+	digest := sha256.Sum256(message)
+	err = rsa.VerifyPSS(publicKey, crypto.SHA256, digest[:], signature, &rsa.PSSOptions{
+		SaltLength: rsa.PSSSaltLengthEqualsHash,
+		Hash:       crypto.SHA256,
+	})
+	if err != nil {
+		return fmt.Errorf("crypto: error verifying signature: %v", err)
+	}
+	return nil
+}
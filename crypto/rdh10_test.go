@@ -0,0 +1,93 @@
+package crypto
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func selfSignedCert(t *testing.T, key *rsa.PrivateKey) *x509.Certificate {
+	t.Helper()
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "go-hbci test"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("error creating self-signed certificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("error parsing self-signed certificate: %v", err)
+	}
+	return cert
+}
+
+func TestRDH10Signer_SignVerifyRoundTrip(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("error generating key: %v", err)
+	}
+	cert := selfSignedCert(t, key)
+	roots := x509.NewCertPool()
+	roots.AddCert(cert)
+
+	signer := &RDH10Signer{Key: key, Certificate: cert, Roots: roots}
+	message := []byte("HBCI test message")
+
+	signature, err := signer.Sign(message)
+	if err != nil {
+		t.Fatalf("Sign() returned error: %v", err)
+	}
+	if err := signer.Verify(message, signature, cert.Raw); err != nil {
+		t.Fatalf("Verify() returned error for a valid signature: %v", err)
+	}
+}
+
+func TestRDH10Signer_VerifyRejectsUntrustedCertificate(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("error generating key: %v", err)
+	}
+	cert := selfSignedCert(t, key)
+	// Roots is empty, so cert must not be trusted even though the signature
+	// itself is valid.
+	signer := &RDH10Signer{Key: key, Certificate: cert, Roots: x509.NewCertPool()}
+	message := []byte("HBCI test message")
+
+	signature, err := signer.Sign(message)
+	if err != nil {
+		t.Fatalf("Sign() returned error: %v", err)
+	}
+	if err := signer.Verify(message, signature, cert.Raw); err == nil {
+		t.Fatal("expected Verify to reject a certificate that does not chain to Roots")
+	}
+}
+
+func TestRDH10Signer_VerifyRejectsTamperedMessage(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("error generating key: %v", err)
+	}
+	cert := selfSignedCert(t, key)
+	roots := x509.NewCertPool()
+	roots.AddCert(cert)
+	signer := &RDH10Signer{Key: key, Certificate: cert, Roots: roots}
+
+	signature, err := signer.Sign([]byte("original message"))
+	if err != nil {
+		t.Fatalf("Sign() returned error: %v", err)
+	}
+	if err := signer.Verify([]byte("tampered message"), signature, cert.Raw); err == nil {
+		t.Fatal("expected Verify to reject a signature computed over a different message")
+	}
+}
@@ -0,0 +1,83 @@
+package dialog
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mitch000001/go-hbci/client"
+	"github.com/mitch000001/go-hbci/segment"
+	"github.com/mitch000001/go-hbci/transport"
+)
+
+// MessageCodec marshals outgoing segments into a HBCI wire message and
+// unmarshals an incoming one back into segments. It is the seam
+// HTTPExchanger plugs into; the concrete wire format lives in the message
+// package rather than here.
+type MessageCodec interface {
+	Marshal(segments ...segment.Segment) ([]byte, error)
+	Unmarshal(message []byte) ([]segment.Segment, error)
+}
+
+// EncryptionHeader is implemented by a decoded segment that carries a RDH
+// envelope, exposing what Config.VerifyEncryptionHeader needs to check it:
+// the EncryptionHeaderSegment (HNVSK) itself, and the raw bytes/signature
+// its accompanying signature header segment (HNSHK) was computed over.
+type EncryptionHeader interface {
+	EncryptionHeader() (header *segment.EncryptionHeaderSegment, signedMessage []byte, signature []byte)
+}
+
+// HTTPExchanger is the default MessageExchanger: it marshals segments with
+// Codec, sends them to Addr with Transport, and unmarshals the reply. If
+// Config.RootCertificates is set, the reply must carry an EncryptionHeader
+// that verifies with Config.VerifyEncryptionHeader, so a message signed by
+// an untrusted party - or one that omits the envelope altogether - is
+// rejected instead of silently trusted.
+type HTTPExchanger struct {
+	Addr      string
+	Transport transport.Transport
+	Codec     MessageCodec
+	Config    *client.Config
+}
+
+// Exchange implements MessageExchanger.
+func (e *HTTPExchanger) Exchange(ctx context.Context, businessSegment segment.Segment, tan *segment.TanRequestSegment) (*segment.TanResponseSegment, []segment.Segment, error) {
+	message, err := e.Codec.Marshal(businessSegment, tan)
+	if err != nil {
+		return nil, nil, fmt.Errorf("dialog: error marshaling message: %v", err)
+	}
+	response, err := e.Transport.Do(ctx, e.Addr, message)
+	if err != nil {
+		return nil, nil, fmt.Errorf("dialog: error sending message: %v", err)
+	}
+	segments, err := e.Codec.Unmarshal(response)
+	if err != nil {
+		return nil, nil, fmt.Errorf("dialog: error unmarshaling reply: %v", err)
+	}
+	if e.Config != nil && e.Config.RootCertificates != nil {
+		envelope, ok := findEncryptionHeader(segments)
+		if !ok {
+			return nil, nil, fmt.Errorf("dialog: RootCertificates configured but reply carries no verifiable encryption header")
+		}
+		header, signedMessage, signature := envelope.EncryptionHeader()
+		if err := e.Config.VerifyEncryptionHeader(header, signedMessage, signature); err != nil {
+			return nil, nil, fmt.Errorf("dialog: error verifying reply: %v", err)
+		}
+	}
+	var tanResponse *segment.TanResponseSegment
+	for _, s := range segments {
+		if response, ok := s.(*segment.TanResponseSegment); ok {
+			tanResponse = response
+			break
+		}
+	}
+	return tanResponse, segments, nil
+}
+
+func findEncryptionHeader(segments []segment.Segment) (EncryptionHeader, bool) {
+	for _, s := range segments {
+		if envelope, ok := s.(EncryptionHeader); ok {
+			return envelope, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,82 @@
+package dialog
+
+import (
+	"context"
+	"crypto/x509"
+	"testing"
+
+	"github.com/mitch000001/go-hbci/client"
+	"github.com/mitch000001/go-hbci/element"
+	"github.com/mitch000001/go-hbci/segment"
+)
+
+type fakeTransport struct {
+	response []byte
+	err      error
+}
+
+func (t *fakeTransport) Do(ctx context.Context, addr string, message []byte) ([]byte, error) {
+	return t.response, t.err
+}
+
+type fakeCodec struct {
+	segments []segment.Segment
+}
+
+func (c *fakeCodec) Marshal(segments ...segment.Segment) ([]byte, error) {
+	return []byte("message"), nil
+}
+
+func (c *fakeCodec) Unmarshal(message []byte) ([]segment.Segment, error) {
+	return c.segments, nil
+}
+
+func TestHTTPExchanger_SkipsVerificationWithoutRootCertificates(t *testing.T) {
+	envelope := &segment.EncryptionHeaderSegment{}
+	envelope.SetSignature([]byte("signed message"), []byte("signature"))
+	exchanger := &HTTPExchanger{
+		Addr:      "https://bank.example/hbci",
+		Transport: &fakeTransport{response: []byte("reply")},
+		Codec:     &fakeCodec{segments: []segment.Segment{envelope}},
+		Config:    &client.Config{},
+	}
+
+	tan := segment.NewTanRequestSegment(element.TanProcess4, "HKTAN", "", "", "")
+	_, reply, err := exchanger.Exchange(context.Background(), newJob(), tan)
+	if err != nil {
+		t.Fatalf("Exchange() returned error: %v", err)
+	}
+	if len(reply) != 1 {
+		t.Fatalf("expected 1 segment in reply, got %d", len(reply))
+	}
+}
+
+func TestHTTPExchanger_RejectsUntrustedEnvelope(t *testing.T) {
+	envelope := &segment.EncryptionHeaderSegment{}
+	envelope.SetSignature([]byte("signed message"), []byte("signature"))
+	exchanger := &HTTPExchanger{
+		Addr:      "https://bank.example/hbci",
+		Transport: &fakeTransport{response: []byte("reply")},
+		Codec:     &fakeCodec{segments: []segment.Segment{envelope}},
+		Config:    &client.Config{RootCertificates: x509.NewCertPool()},
+	}
+
+	tan := segment.NewTanRequestSegment(element.TanProcess4, "HKTAN", "", "", "")
+	if _, _, err := exchanger.Exchange(context.Background(), newJob(), tan); err == nil {
+		t.Fatal("expected Exchange to reject an envelope with no certificate once RootCertificates is configured")
+	}
+}
+
+func TestHTTPExchanger_RejectsReplyWithoutEnvelope(t *testing.T) {
+	exchanger := &HTTPExchanger{
+		Addr:      "https://bank.example/hbci",
+		Transport: &fakeTransport{response: []byte("reply")},
+		Codec:     &fakeCodec{segments: []segment.Segment{newJob()}},
+		Config:    &client.Config{RootCertificates: x509.NewCertPool()},
+	}
+
+	tan := segment.NewTanRequestSegment(element.TanProcess4, "HKTAN", "", "", "")
+	if _, _, err := exchanger.Exchange(context.Background(), newJob(), tan); err == nil {
+		t.Fatal("expected Exchange to reject a reply with no encryption header once RootCertificates is configured")
+	}
+}
@@ -0,0 +1,57 @@
+// Package dialog orchestrates multi-message HBCI exchanges - flows that
+// cannot be expressed as a single request/response, such as the two-step-TAN
+// procedure - on top of the segment and transport packages.
+package dialog
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mitch000001/go-hbci/client"
+	"github.com/mitch000001/go-hbci/element"
+	"github.com/mitch000001/go-hbci/segment"
+)
+
+// MessageExchanger sends businessSegment together with tan (the two-step-TAN
+// header for it) to the bank in a single HBCI message and unmarshals the
+// reply. It returns the TanResponseSegment (HITAN) from the reply, if the
+// bank sent one, alongside every other segment in the reply. It is the seam
+// TanOrder plugs into; HTTPExchanger implements it by packing segments into
+// a HBCI message, sending it over a transport.Transport and unmarshaling the
+// response.
+type MessageExchanger interface {
+	Exchange(ctx context.Context, businessSegment segment.Segment, tan *segment.TanRequestSegment) (tanResponse *segment.TanResponseSegment, reply []segment.Segment, err error)
+}
+
+// TanOrder drives a two-step-TAN order end to end:
+//
+//  1. it sends businessSegment announced with element.TanProcess4,
+//  2. hands the domain.TanChallenge the bank answers with to tanCallback,
+//  3. and resubmits businessSegment with element.TanProcess2, carrying the
+//     TAN tanCallback returned and the OrderReference the bank issued for
+//     step 1, so the bank can correlate the TAN with the announced job.
+//
+// ctx is passed through to tanCallback so a UI can cancel a pending
+// chipTAN/pushTAN/photoTAN prompt.
+func TanOrder(ctx context.Context, exchanger MessageExchanger, businessSegment segment.Segment, tanMediumName string, tanCallback client.TanCallback) ([]segment.Segment, error) {
+	announce := segment.NewTanRequestSegment(element.TanProcess4, businessSegment.ID(), "", "", tanMediumName)
+	challenge, _, err := exchanger.Exchange(ctx, businessSegment, announce)
+	if err != nil {
+		return nil, fmt.Errorf("dialog: error announcing job for TAN: %v", err)
+	}
+	if challenge == nil {
+		return nil, fmt.Errorf("dialog: bank did not answer %q with a TAN challenge", businessSegment.ID())
+	}
+
+	tan, err := tanCallback(ctx, challenge.TanChallenge())
+	if err != nil {
+		return nil, fmt.Errorf("dialog: error obtaining TAN: %v", err)
+	}
+
+	submit := segment.NewTanRequestSegment(element.TanProcess2, businessSegment.ID(), challenge.OrderReference.Val(), tan, tanMediumName)
+	_, reply, err := exchanger.Exchange(ctx, businessSegment, submit)
+	if err != nil {
+		return nil, fmt.Errorf("dialog: error submitting TAN: %v", err)
+	}
+	return reply, nil
+}
@@ -0,0 +1,110 @@
+package dialog
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mitch000001/go-hbci/domain"
+	"github.com/mitch000001/go-hbci/element"
+	"github.com/mitch000001/go-hbci/segment"
+)
+
+// fakeExchanger answers Exchange calls from a canned list of responses, one
+// per call, and records what it was asked to send.
+type fakeExchanger struct {
+	responses []*segment.TanResponseSegment
+	sent      []*segment.TanRequestSegment
+}
+
+func (f *fakeExchanger) Exchange(ctx context.Context, businessSegment segment.Segment, tan *segment.TanRequestSegment) (*segment.TanResponseSegment, []segment.Segment, error) {
+	f.sent = append(f.sent, tan)
+	i := len(f.sent) - 1
+	if i >= len(f.responses) {
+		return nil, []segment.Segment{businessSegment}, nil
+	}
+	response := f.responses[i]
+	if response == nil {
+		return nil, []segment.Segment{businessSegment}, nil
+	}
+	return response, []segment.Segment{response}, nil
+}
+
+// newJob stands in for a business segment (e.g. HKCCS); TanOrder only relies
+// on it satisfying segment.Segment and returning a stable ID().
+func newJob() segment.Segment {
+	return segment.NewTanRequestSegment(element.TanProcess1, "HKCCS", "", "", "")
+}
+
+func TestTanOrder(t *testing.T) {
+	challenge := &segment.TanResponseSegment{
+		TanProcess:     element.NewTanProcess(element.TanProcess4),
+		OrderReference: element.NewAlphaNumeric("REF123", 35),
+		Challenge:      element.NewAlphaNumeric("Please confirm in your app", 2048),
+	}
+	exchanger := &fakeExchanger{responses: []*segment.TanResponseSegment{challenge, nil}}
+
+	var gotChallenge domain.TanChallenge
+	var gotCtx context.Context
+	callback := func(ctx context.Context, c domain.TanChallenge) (string, error) {
+		gotCtx = ctx
+		gotChallenge = c
+		return "123456", nil
+	}
+
+	ctx := context.Background()
+	if _, err := TanOrder(ctx, exchanger, newJob(), "", callback); err != nil {
+		t.Fatalf("TanOrder() returned error: %v", err)
+	}
+
+	if len(exchanger.sent) != 2 {
+		t.Fatalf("expected 2 exchanges, got %d", len(exchanger.sent))
+	}
+	if got := exchanger.sent[0].TanProcess.Process.Val(); got != element.TanProcess4 {
+		t.Errorf("first request: expected process %q, got %q", element.TanProcess4, got)
+	}
+	if got := exchanger.sent[1].TanProcess.Process.Val(); got != element.TanProcess2 {
+		t.Errorf("second request: expected process %q, got %q", element.TanProcess2, got)
+	}
+	if got := exchanger.sent[1].OrderReference.Val(); got != "REF123" {
+		t.Errorf("second request: expected OrderReference %q, got %q", "REF123", got)
+	}
+	if got := exchanger.sent[1].TAN.Val(); got != "123456" {
+		t.Errorf("second request: expected TAN %q, got %q", "123456", got)
+	}
+	if gotChallenge.Reference != "REF123" {
+		t.Errorf("expected callback challenge reference %q, got %q", "REF123", gotChallenge.Reference)
+	}
+	if gotCtx != ctx {
+		t.Errorf("expected the callback to receive the caller's ctx")
+	}
+}
+
+func TestTanOrder_NoChallenge(t *testing.T) {
+	exchanger := &fakeExchanger{responses: []*segment.TanResponseSegment{nil}}
+	callback := func(ctx context.Context, c domain.TanChallenge) (string, error) {
+		t.Fatal("tanCallback should not be called without a challenge")
+		return "", nil
+	}
+	if _, err := TanOrder(context.Background(), exchanger, newJob(), "", callback); err == nil {
+		t.Fatal("expected an error when the bank does not return a challenge")
+	}
+}
+
+func TestTanOrder_CallbackError(t *testing.T) {
+	challenge := &segment.TanResponseSegment{
+		TanProcess:     element.NewTanProcess(element.TanProcess4),
+		OrderReference: element.NewAlphaNumeric("REF123", 35),
+	}
+	exchanger := &fakeExchanger{responses: []*segment.TanResponseSegment{challenge}}
+	wantErr := errors.New("user cancelled")
+	callback := func(ctx context.Context, c domain.TanChallenge) (string, error) {
+		return "", wantErr
+	}
+	if _, err := TanOrder(context.Background(), exchanger, newJob(), "", callback); err == nil {
+		t.Fatal("expected TanOrder to propagate the callback error")
+	}
+	if len(exchanger.sent) != 1 {
+		t.Fatalf("expected TanOrder to stop after the callback error, got %d exchanges", len(exchanger.sent))
+	}
+}
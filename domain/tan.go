@@ -0,0 +1,24 @@
+package domain
+
+import "time"
+
+// TanChallenge represents the challenge and metadata a bank returns for a
+// two-step-TAN order (HITAN) that requires a TAN before it can be executed.
+type TanChallenge struct {
+	// Process is the TAN process variant the challenge was issued for (see
+	// element.TanProcess1 - element.TanProcess4)
+	Process string
+	// Reference identifies the originally submitted order so the TAN can
+	// later be submitted against it using element.TanProcess2
+	Reference string
+	// Challenge holds the human readable challenge text, e.g. instructions
+	// displayed by a pushTAN or photoTAN app
+	Challenge string
+	// HHDUC carries the raw HHD-UC ("flicker code") challenge for chipTAN
+	// generators, if one was issued alongside Challenge
+	HHDUC []byte
+	// TanMediaName names the TAN medium the challenge was issued for
+	TanMediaName string
+	// ValidUntil marks the point in time the challenge expires
+	ValidUntil time.Time
+}
@@ -429,3 +429,33 @@ func (c *CertificateDataElement) GroupDataElements() []DataElement {
 		c.Content,
 	}
 }
+
+// NewX509Certificate embodies the DER encoding of an X.509 certificate into
+// a CertificateDataElement
+func NewX509Certificate(derBytes []byte) *CertificateDataElement {
+	return NewCertificate(3, derBytes)
+}
+
+// NewRDH10SignatureAlgorithm creates a SignatureAlgorithm ready to use for
+// the RDH-10 profile (RSASSA-PSS)
+func NewRDH10SignatureAlgorithm() *SignatureAlgorithmDataElement {
+	s := &SignatureAlgorithmDataElement{
+		Usage:         NewAlphaNumeric("6", 3),
+		Algorithm:     NewAlphaNumeric("10", 3),
+		OperationMode: NewAlphaNumeric("19", 3),
+	}
+	s.DataElement = NewDataElementGroup(signatureAlgorithmDEG, 3, s)
+	return s
+}
+
+// NewRDH10HashAlgorithm creates a HashAlgorithm ready to use for the RDH-10
+// profile (SHA-256)
+func NewRDH10HashAlgorithm() *HashAlgorithmDataElement {
+	h := &HashAlgorithmDataElement{
+		Usage:            NewAlphaNumeric("1", 3),
+		Algorithm:        NewAlphaNumeric("3", 3),
+		AlgorithmParamID: NewAlphaNumeric("1", 3),
+	}
+	h.DataElement = NewDataElementGroup(hashAlgorithmDEG, 4, h)
+	return h
+}
@@ -0,0 +1,166 @@
+package element
+
+import "fmt"
+
+// TAN process variants used by the two-step-TAN procedure (HKTAN/HITAN)
+const (
+	// TanProcess1 requests the one-step TAN process
+	TanProcess1 = "1"
+	// TanProcess2 submits a previously announced job together with the TAN
+	TanProcess2 = "2"
+	// TanProcess3 queries the status of a pending two-step job
+	TanProcess3 = "3"
+	// TanProcess4 announces a job and requests a TAN challenge for it
+	TanProcess4 = "4"
+)
+
+// NewTanProcess creates a new TanProcessDataElement for the given process
+// variant
+func NewTanProcess(process string) *TanProcessDataElement {
+	switch process {
+	case TanProcess1, TanProcess2, TanProcess3, TanProcess4:
+	default:
+		panic(fmt.Errorf("TanProcess must be one of '1', '2', '3' or '4'"))
+	}
+	t := &TanProcessDataElement{
+		Process: NewAlphaNumeric(process, 1),
+	}
+	t.DataElement = NewDataElementGroup(tanProcessDEG, 1, t)
+	return t
+}
+
+// TanProcessDataElement represents the TAN process variant used within a
+// two-step-TAN segment
+type TanProcessDataElement struct {
+	DataElement
+	// "1" for the one-step TAN process
+	// "2" for submitting a job together with the TAN
+	// "3" for querying the status of a pending job
+	// "4" for announcing a job and requesting a TAN challenge
+	Process *AlphaNumericDataElement
+}
+
+// GroupDataElements returns the grouped DataElements
+func (t *TanProcessDataElement) GroupDataElements() []DataElement {
+	return []DataElement{
+		t.Process,
+	}
+}
+
+// UnmarshalHBCI unmarshals value into the DataElement
+func (t *TanProcessDataElement) UnmarshalHBCI(value []byte) error {
+	elements, err := ExtractElements(value)
+	if err != nil {
+		return err
+	}
+	if len(elements) < 1 {
+		return fmt.Errorf("malformed marshaled value")
+	}
+	t.DataElement = NewDataElementGroup(tanProcessDEG, 1, t)
+	if len(elements) > 0 && len(elements[0]) > 0 {
+		t.Process = &AlphaNumericDataElement{}
+		err = t.Process.UnmarshalHBCI(elements[0])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NewTanMedium creates a new TanMediumDataElement identifying a registered
+// TAN medium by name
+func NewTanMedium(name string) *TanMediumDataElement {
+	t := &TanMediumDataElement{
+		Name: NewAlphaNumeric(name, 32),
+	}
+	t.DataElement = NewDataElementGroup(tanMediumDEG, 1, t)
+	return t
+}
+
+// TanMediumDataElement names a TAN medium registered with the bank, e.g. the
+// mobile phone for pushTAN or the designation of a chipTAN generator
+type TanMediumDataElement struct {
+	DataElement
+	Name *AlphaNumericDataElement
+}
+
+// GroupDataElements returns the grouped DataElements
+func (t *TanMediumDataElement) GroupDataElements() []DataElement {
+	return []DataElement{
+		t.Name,
+	}
+}
+
+// UnmarshalHBCI unmarshals value into the DataElement
+func (t *TanMediumDataElement) UnmarshalHBCI(value []byte) error {
+	elements, err := ExtractElements(value)
+	if err != nil {
+		return err
+	}
+	if len(elements) < 1 {
+		return fmt.Errorf("malformed marshaled value")
+	}
+	t.DataElement = NewDataElementGroup(tanMediumDEG, 1, t)
+	if len(elements) > 0 && len(elements[0]) > 0 {
+		t.Name = &AlphaNumericDataElement{}
+		err = t.Name.UnmarshalHBCI(elements[0])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NewHHDUCChallenge wraps a HHD-UC ("flicker code") challenge, as generated
+// for chipTAN readers, into a BinaryDataElement
+func NewHHDUCChallenge(challenge []byte) *HHDUCChallengeDataElement {
+	h := &HHDUCChallengeDataElement{
+		Content: NewBinary(challenge, 996),
+	}
+	h.DataElement = NewDataElementGroup(hhdUCChallengeDEG, 1, h)
+	return h
+}
+
+// HHDUCChallengeDataElement carries the raw HHD-UC challenge bytes a chipTAN
+// generator turns into a flicker code
+type HHDUCChallengeDataElement struct {
+	DataElement
+	Content *BinaryDataElement
+}
+
+// ChallengeHHD_UC is the common name used by the spec for the binary HHD-UC
+// challenge
+type ChallengeHHD_UC = HHDUCChallengeDataElement
+
+// GroupDataElements returns the grouped DataElements
+func (h *HHDUCChallengeDataElement) GroupDataElements() []DataElement {
+	return []DataElement{
+		h.Content,
+	}
+}
+
+// UnmarshalHBCI unmarshals value into the DataElement
+func (h *HHDUCChallengeDataElement) UnmarshalHBCI(value []byte) error {
+	elements, err := ExtractElements(value)
+	if err != nil {
+		return err
+	}
+	if len(elements) < 1 {
+		return fmt.Errorf("malformed marshaled value")
+	}
+	h.DataElement = NewDataElementGroup(hhdUCChallengeDEG, 1, h)
+	if len(elements) > 0 && len(elements[0]) > 0 {
+		h.Content = &BinaryDataElement{}
+		err = h.Content.UnmarshalHBCI(elements[0])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+const (
+	tanProcessDEG     = "TAN2StepProcess"
+	tanMediumDEG      = "TANMedium"
+	hhdUCChallengeDEG = "ChallengeHHDUC"
+)
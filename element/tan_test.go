@@ -0,0 +1,37 @@
+package element
+
+import "testing"
+
+func TestNewTanProcess(t *testing.T) {
+	for _, process := range []string{TanProcess1, TanProcess2, TanProcess3, TanProcess4} {
+		t.Run(process, func(t *testing.T) {
+			tan := NewTanProcess(process)
+			if got := tan.Process.Val(); got != process {
+				t.Errorf("expected Process %q, got %q", process, got)
+			}
+		})
+	}
+}
+
+func TestNewTanProcess_PanicsOnInvalidProcess(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected NewTanProcess to panic for an unknown process")
+		}
+	}()
+	NewTanProcess("5")
+}
+
+func TestNewTanMedium(t *testing.T) {
+	medium := NewTanMedium("iPhone 12")
+	if got := medium.Name.Val(); got != "iPhone 12" {
+		t.Errorf("expected Name %q, got %q", "iPhone 12", got)
+	}
+}
+
+func TestNewHHDUCChallenge(t *testing.T) {
+	challenge := NewHHDUCChallenge([]byte{1, 2, 3})
+	if got := challenge.Content.Val(); string(got) != string([]byte{1, 2, 3}) {
+		t.Errorf("expected Content %v, got %v", []byte{1, 2, 3}, got)
+	}
+}
@@ -1,8 +1,12 @@
 package segment
 
 import (
+	"crypto/rsa"
+	"crypto/x509"
+	"fmt"
 	"time"
 
+	gohbcicrypto "github.com/mitch000001/go-hbci/crypto"
 	"github.com/mitch000001/go-hbci/domain"
 	"github.com/mitch000001/go-hbci/element"
 )
@@ -35,6 +39,32 @@ func NewEncryptionHeaderSegment(clientSystemId string, keyName domain.KeyName, k
 	return e
 }
 
+// NewEncryptionHeaderSegmentFromKeyStore creates an EncryptionHeaderSegment
+// (HNVSK) for the RDH profile like NewEncryptionHeaderSegment, but loads
+// keyName's encryption key from keyStore instead of requiring its raw bytes
+// in memory, so the private key never has to leave keyStore.
+func NewEncryptionHeaderSegmentFromKeyStore(clientSystemId string, keyName domain.KeyName, keyStore gohbcicrypto.KeyStore) (*EncryptionHeaderSegment, error) {
+	decrypter, err := keyStore.LoadEncryptionKey(keyName)
+	if err != nil {
+		return nil, fmt.Errorf("segment: error loading encryption key: %v", err)
+	}
+	rsaKey, ok := decrypter.Public().(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("segment: encryption key is not a RSA key")
+	}
+	e := &EncryptionHeaderSegment{
+		SecurityFunction:     element.NewAlphaNumeric("4", 3),
+		SecuritySupplierRole: element.NewAlphaNumeric("1", 3),
+		SecurityID:           element.NewRDHSecurityIdentification(element.SecurityHolderMessageSender, clientSystemId),
+		SecurityDate:         element.NewSecurityDate(element.SecurityTimestamp, time.Now()),
+		EncryptionAlgorithm:  element.NewRDHEncryptionAlgorithm(x509.MarshalPKCS1PublicKey(rsaKey)),
+		KeyName:              element.NewKeyName(keyName),
+		CompressionFunction:  element.NewAlphaNumeric("0", 3),
+	}
+	e.Segment = NewBasicSegment(998, e)
+	return e, nil
+}
+
 //go:generate go run ../cmd/unmarshaler/unmarshaler_generator.go -segment EncryptionHeaderSegment
 
 type EncryptionHeaderSegment struct {
@@ -52,6 +82,8 @@ type EncryptionHeaderSegment struct {
 	KeyName              *element.KeyNameDataElement
 	CompressionFunction  *element.AlphaNumericDataElement
 	Certificate          *element.CertificateDataElement
+
+	signedEnvelope signedEnvelope
 }
 
 func (e *EncryptionHeaderSegment) Version() int         { return 2 }
@@ -70,4 +102,29 @@ func (e *EncryptionHeaderSegment) elements() []element.DataElement {
 		e.CompressionFunction,
 		e.Certificate,
 	}
-}
\ No newline at end of file
+}
+
+// signedMessage and signature are not part of the HNVSK wire format: the
+// signature over a RDH-10 message lives in its accompanying HNSHK signature
+// header segment, not in HNVSK itself. A MessageCodec.Unmarshal
+// implementation decodes a full message, pairs each HNVSK segment with its
+// HNSHK counterpart and calls SetSignature so the pairing survives past
+// Unmarshal without the caller having to track both segments separately.
+type signedEnvelope struct {
+	signedMessage []byte
+	signature     []byte
+}
+
+// SetSignature attaches the raw bytes an accompanying HNSHK signature header
+// segment was computed over, and its signature value, to this decoded HNVSK
+// segment. It must be called while unmarshaling a message, before the
+// segment is handed to anything that verifies it via EncryptionHeader.
+func (e *EncryptionHeaderSegment) SetSignature(signedMessage, signature []byte) {
+	e.signedEnvelope = signedEnvelope{signedMessage: signedMessage, signature: signature}
+}
+
+// EncryptionHeader implements dialog.EncryptionHeader, exposing the HNSHK
+// signed bytes and signature value SetSignature attached to this segment.
+func (e *EncryptionHeaderSegment) EncryptionHeader() (*EncryptionHeaderSegment, []byte, []byte) {
+	return e, e.signedEnvelope.signedMessage, e.signedEnvelope.signature
+}
@@ -0,0 +1,31 @@
+package segment
+
+import "testing"
+
+func TestEncryptionHeaderSegment_EncryptionHeader(t *testing.T) {
+	e := &EncryptionHeaderSegment{}
+	e.SetSignature([]byte("signed message"), []byte("signature"))
+
+	header, signedMessage, signature := e.EncryptionHeader()
+	if header != e {
+		t.Errorf("expected EncryptionHeader to return the segment itself, got %v", header)
+	}
+	if string(signedMessage) != "signed message" {
+		t.Errorf("expected signedMessage %q, got %q", "signed message", signedMessage)
+	}
+	if string(signature) != "signature" {
+		t.Errorf("expected signature %q, got %q", "signature", signature)
+	}
+}
+
+func TestEncryptionHeaderSegment_EncryptionHeader_NoSignatureSet(t *testing.T) {
+	e := &EncryptionHeaderSegment{}
+
+	_, signedMessage, signature := e.EncryptionHeader()
+	if signedMessage != nil {
+		t.Errorf("expected no signedMessage before SetSignature is called, got %q", signedMessage)
+	}
+	if signature != nil {
+		t.Errorf("expected no signature before SetSignature is called, got %q", signature)
+	}
+}
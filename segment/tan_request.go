@@ -0,0 +1,65 @@
+package segment
+
+import (
+	"github.com/mitch000001/go-hbci/element"
+)
+
+// NewTanRequestSegment creates a new TanRequestSegment (HKTAN).
+//
+// Use element.TanProcess4 to announce orderSegmentType and receive a
+// challenge for it, and element.TanProcess2 to submit tan for the
+// orderReference returned in that challenge; tan is ignored for every other
+// process.
+func NewTanRequestSegment(process string, orderSegmentType string, orderReference string, tan string, tanMediumName string) *TanRequestSegment {
+	t := &TanRequestSegment{
+		TanProcess:     element.NewTanProcess(process),
+		SegmentType:    element.NewAlphaNumeric(orderSegmentType, 6),
+		OrderReference: element.NewAlphaNumeric(orderReference, 35),
+	}
+	if tan != "" && process == element.TanProcess2 {
+		t.TAN = element.NewAlphaNumeric(tan, 35)
+	}
+	if tanMediumName != "" {
+		t.TanMediumName = element.NewTanMedium(tanMediumName)
+	}
+	t.Segment = NewBasicSegment(4, t)
+	return t
+}
+
+//go:generate go run ../cmd/unmarshaler/unmarshaler_generator.go -segment TanRequestSegment
+
+// TanRequestSegment represents the HKTAN segment used to drive the
+// two-step-TAN procedure: announcing a job and requesting a challenge for
+// it (element.TanProcess4), and later submitting the TAN for that job
+// (element.TanProcess2).
+type TanRequestSegment struct {
+	Segment
+	TanProcess *element.TanProcessDataElement
+	// SegmentType is the segment identifier (e.g. "HKCCS") of the order this
+	// TAN request belongs to
+	SegmentType *element.AlphaNumericDataElement
+	// OrderReference references the Auftragsreferenz returned by the bank for
+	// element.TanProcess4 when submitting the TAN for element.TanProcess2
+	OrderReference *element.AlphaNumericDataElement
+	// TAN carries the TAN value the user entered in response to a challenge;
+	// only sent for element.TanProcess2
+	TAN *element.AlphaNumericDataElement
+	// TanMediumName selects a specific registered TAN medium; required for
+	// pushTAN/photoTAN if more than one is registered
+	TanMediumName *element.TanMediumDataElement
+}
+
+func (t *TanRequestSegment) Version() int         { return 6 }
+func (t *TanRequestSegment) ID() string           { return "HKTAN" }
+func (t *TanRequestSegment) referencedId() string { return "" }
+func (t *TanRequestSegment) sender() string       { return senderUser }
+
+func (t *TanRequestSegment) elements() []element.DataElement {
+	return []element.DataElement{
+		t.TanProcess,
+		t.SegmentType,
+		t.OrderReference,
+		t.TAN,
+		t.TanMediumName,
+	}
+}
@@ -0,0 +1,39 @@
+package segment
+
+import (
+	"testing"
+
+	"github.com/mitch000001/go-hbci/element"
+)
+
+func TestNewTanRequestSegment_Announce(t *testing.T) {
+	s := NewTanRequestSegment(element.TanProcess4, "HKCCS", "", "", "")
+	if got := s.TanProcess.Process.Val(); got != element.TanProcess4 {
+		t.Errorf("expected TanProcess %q, got %q", element.TanProcess4, got)
+	}
+	if got := s.SegmentType.Val(); got != "HKCCS" {
+		t.Errorf("expected SegmentType %q, got %q", "HKCCS", got)
+	}
+	if s.TAN != nil {
+		t.Error("expected no TAN element to be set when announcing a job")
+	}
+	if s.TanMediumName != nil {
+		t.Error("expected no TanMediumName element to be set when none is given")
+	}
+}
+
+func TestNewTanRequestSegment_SubmitTan(t *testing.T) {
+	s := NewTanRequestSegment(element.TanProcess2, "HKCCS", "REF123", "123456", "iPhone 12")
+	if got := s.OrderReference.Val(); got != "REF123" {
+		t.Errorf("expected OrderReference %q, got %q", "REF123", got)
+	}
+	if s.TAN == nil {
+		t.Fatal("expected a TAN element to be set when submitting a TAN")
+	}
+	if got := s.TAN.Val(); got != "123456" {
+		t.Errorf("expected TAN %q, got %q", "123456", got)
+	}
+	if s.TanMediumName == nil || s.TanMediumName.Name.Val() != "iPhone 12" {
+		t.Error("expected TanMediumName to be set to the given medium")
+	}
+}
@@ -0,0 +1,80 @@
+package segment
+
+import (
+	"time"
+
+	"github.com/mitch000001/go-hbci/domain"
+	"github.com/mitch000001/go-hbci/element"
+)
+
+//go:generate go run ../cmd/unmarshaler/unmarshaler_generator.go -segment TanResponseSegment
+
+// TanResponseSegment represents the HITAN segment a bank sends in response
+// to a TanRequestSegment. It carries the TAN challenge the user has to
+// answer (Challenge, and for chipTAN HHDUCChallenge) along with the
+// OrderReference the TAN has to be submitted against.
+type TanResponseSegment struct {
+	Segment
+	TanProcess *element.TanProcessDataElement
+	// JobHashValue lets the bank detect a modified job between the challenge
+	// and the TAN submission
+	JobHashValue *element.BinaryDataElement
+	// OrderReference must be sent back unchanged as TanRequestSegment.OrderReference
+	// together with the TAN
+	OrderReference *element.AlphaNumericDataElement
+	// Challenge holds the human readable challenge text, e.g. instructions
+	// for a pushTAN/photoTAN app
+	Challenge *element.AlphaNumericDataElement
+	// HHDUCChallenge carries the raw HHD-UC ("flicker code") challenge for
+	// chipTAN generators, if one was issued
+	HHDUCChallenge *element.HHDUCChallengeDataElement
+	// ChallengeValidUntil marks the point in time the challenge expires
+	ChallengeValidUntil *element.SecurityDateDataElement
+	// TanMediumName names the TAN medium the challenge was issued for
+	TanMediumName *element.TanMediumDataElement
+}
+
+func (t *TanResponseSegment) Version() int         { return 6 }
+func (t *TanResponseSegment) ID() string           { return "HITAN" }
+func (t *TanResponseSegment) referencedId() string { return "HKTAN" }
+func (t *TanResponseSegment) sender() string       { return senderBank }
+
+func (t *TanResponseSegment) elements() []element.DataElement {
+	return []element.DataElement{
+		t.TanProcess,
+		t.JobHashValue,
+		t.OrderReference,
+		t.Challenge,
+		t.HHDUCChallenge,
+		t.ChallengeValidUntil,
+		t.TanMediumName,
+	}
+}
+
+// TanChallenge extracts the domain.TanChallenge carried by this segment so
+// it can be handed to a client.TanCallback.
+func (t *TanResponseSegment) TanChallenge() domain.TanChallenge {
+	challenge := domain.TanChallenge{
+		Process:   t.TanProcess.Process.Val(),
+		Reference: t.OrderReference.Val(),
+	}
+	if t.Challenge != nil {
+		challenge.Challenge = t.Challenge.Val()
+	}
+	if t.HHDUCChallenge != nil {
+		challenge.HHDUC = t.HHDUCChallenge.Content.Val()
+	}
+	if t.TanMediumName != nil {
+		challenge.TanMediaName = t.TanMediumName.Name.Val()
+	}
+	if t.ChallengeValidUntil != nil {
+		date := t.ChallengeValidUntil.Date.Val()
+		timeOfDay := t.ChallengeValidUntil.Time.Val()
+		challenge.ValidUntil = time.Date(
+			date.Year(), date.Month(), date.Day(),
+			timeOfDay.Hour(), timeOfDay.Minute(), timeOfDay.Second(), 0,
+			date.Location(),
+		)
+	}
+	return challenge
+}
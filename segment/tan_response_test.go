@@ -0,0 +1,59 @@
+package segment
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mitch000001/go-hbci/element"
+)
+
+func TestTanResponseSegment_TanChallenge(t *testing.T) {
+	validUntil := time.Now().Add(5 * time.Minute)
+	s := &TanResponseSegment{
+		TanProcess:          element.NewTanProcess(element.TanProcess4),
+		OrderReference:      element.NewAlphaNumeric("REF123", 35),
+		Challenge:           element.NewAlphaNumeric("Please confirm in your app", 2048),
+		HHDUCChallenge:      element.NewHHDUCChallenge([]byte{1, 2, 3}),
+		ChallengeValidUntil: element.NewSecurityDate(element.SecurityTimestamp, validUntil),
+		TanMediumName:       element.NewTanMedium("iPhone 12"),
+	}
+
+	challenge := s.TanChallenge()
+	if challenge.Process != element.TanProcess4 {
+		t.Errorf("expected Process %q, got %q", element.TanProcess4, challenge.Process)
+	}
+	if challenge.Reference != "REF123" {
+		t.Errorf("expected Reference %q, got %q", "REF123", challenge.Reference)
+	}
+	if challenge.Challenge != "Please confirm in your app" {
+		t.Errorf("expected Challenge %q, got %q", "Please confirm in your app", challenge.Challenge)
+	}
+	if string(challenge.HHDUC) != string([]byte{1, 2, 3}) {
+		t.Errorf("expected HHDUC %v, got %v", []byte{1, 2, 3}, challenge.HHDUC)
+	}
+	if challenge.TanMediaName != "iPhone 12" {
+		t.Errorf("expected TanMediaName %q, got %q", "iPhone 12", challenge.TanMediaName)
+	}
+	want := time.Date(validUntil.Year(), validUntil.Month(), validUntil.Day(),
+		validUntil.Hour(), validUntil.Minute(), validUntil.Second(), 0, validUntil.Location())
+	if !challenge.ValidUntil.Equal(want) {
+		t.Errorf("expected ValidUntil %s, got %s", want, challenge.ValidUntil)
+	}
+}
+
+func TestTanResponseSegment_TanChallenge_MinimalFields(t *testing.T) {
+	s := &TanResponseSegment{
+		TanProcess:     element.NewTanProcess(element.TanProcess4),
+		OrderReference: element.NewAlphaNumeric("REF123", 35),
+	}
+	challenge := s.TanChallenge()
+	if challenge.Reference != "REF123" {
+		t.Errorf("expected Reference %q, got %q", "REF123", challenge.Reference)
+	}
+	if challenge.Challenge != "" {
+		t.Errorf("expected empty Challenge, got %q", challenge.Challenge)
+	}
+	if challenge.HHDUC != nil {
+		t.Errorf("expected no HHDUC, got %v", challenge.HHDUC)
+	}
+}
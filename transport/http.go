@@ -0,0 +1,154 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// transientResponseCodes are HBCI Rückmeldungscodes that indicate a
+// transient, retryable failure - the bank could not process the message
+// right now (e.g. it is throttling the connection or the dialog is busy)
+// rather than rejecting it outright.
+var transientResponseCodes = map[int]bool{
+	9050: true, // Nachricht abgelehnt, zu viele Anfragen
+	9210: true, // Zeitüberschreitung (Timeout)
+}
+
+// IsTransientResponseCode reports whether code is a HBCI Rückmeldungscode
+// that is safe to retry.
+func IsTransientResponseCode(code int) bool {
+	return transientResponseCodes[code]
+}
+
+// ContainsTransientResponseCode scans a raw HBCI response for a
+// Rückmeldungscode recognized by IsTransientResponseCode. HBCI encodes a
+// Rückmeldungscode as ASCII decimal digits between ':' element separators,
+// e.g. "...:9050:Nachricht...", so a plain substring scan finds it without
+// pulling in the full segment parser.
+func ContainsTransientResponseCode(body []byte) bool {
+	for code := range transientResponseCodes {
+		if bytes.Contains(body, []byte(fmt.Sprintf(":%d:", code))) {
+			return true
+		}
+	}
+	return false
+}
+
+// HTTPTransport is the default Transport: it POSTs the HBCI message over
+// HTTP, applies a token-bucket rate limit and retries transient failures
+// with exponential backoff and jitter.
+type HTTPTransport struct {
+	Client *http.Client
+	// MaxRetries bounds how many times a transient failure is retried before
+	// Do gives up and returns the last error
+	MaxRetries int
+	// BaseBackoff is the upper bound of the jittered backoff after the first
+	// failed attempt; the bound doubles after every further retry
+	BaseBackoff time.Duration
+
+	limiter *rate.Limiter
+}
+
+// NewHTTPTransport creates a HTTPTransport rate limited to rps requests per
+// second, allowing bursts of up to burst requests.
+func NewHTTPTransport(rps float64, burst int) *HTTPTransport {
+	return &HTTPTransport{
+		Client:      http.DefaultClient,
+		MaxRetries:  5,
+		BaseBackoff: 500 * time.Millisecond,
+		limiter:     rate.NewLimiter(rate.Limit(rps), burst),
+	}
+}
+
+// Do sends message to addr, honoring ctx cancellation and the configured
+// rate limit, and retries transient failures with exponential backoff and
+// jitter.
+func (t *HTTPTransport) Do(ctx context.Context, addr string, message []byte) ([]byte, error) {
+	var lastErr error
+	for attempt := 0; attempt <= t.MaxRetries; attempt++ {
+		if attempt > 0 {
+			if err := sleep(ctx, t.backoff(attempt)); err != nil {
+				return nil, err
+			}
+		}
+		if err := t.limiter.Wait(ctx); err != nil {
+			return nil, fmt.Errorf("transport: error waiting for rate limiter: %v", err)
+		}
+		response, err := t.do(ctx, addr, message)
+		if err == nil {
+			return response, nil
+		}
+		lastErr = err
+		if !isTransient(err) {
+			return nil, err
+		}
+	}
+	return nil, fmt.Errorf("transport: giving up after %d retries: %v", t.MaxRetries, lastErr)
+}
+
+// backoff returns the exponential backoff with full jitter for the given
+// (1-indexed) retry attempt.
+func (t *HTTPTransport) backoff(attempt int) time.Duration {
+	max := t.BaseBackoff * time.Duration(uint(1)<<uint(attempt-1))
+	return time.Duration(rand.Int63n(int64(max)))
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (t *HTTPTransport) do(ctx context.Context, addr string, message []byte) ([]byte, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, addr, bytes.NewReader(message))
+	if err != nil {
+		return nil, fmt.Errorf("transport: error building request: %v", err)
+	}
+	request.Header.Set("Content-Type", "application/octet-stream")
+	response, err := t.Client.Do(request)
+	if err != nil {
+		return nil, &transientError{err: fmt.Errorf("transport: error sending request: %v", err)}
+	}
+	defer response.Body.Close()
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, &transientError{err: fmt.Errorf("transport: error reading response: %v", err)}
+	}
+	if response.StatusCode != http.StatusOK {
+		err := fmt.Errorf("transport: unexpected status code %d", response.StatusCode)
+		if response.StatusCode >= 500 {
+			// The bank's server failed to process the request rather than
+			// rejecting it outright; safe to retry.
+			return nil, &transientError{err: err}
+		}
+		return nil, err
+	}
+	if ContainsTransientResponseCode(body) {
+		return nil, &transientError{err: fmt.Errorf("transport: response carries a transient HBCI Rückmeldungscode")}
+	}
+	return body, nil
+}
+
+// transientError marks an error as safe to retry.
+type transientError struct {
+	err error
+}
+
+func (e *transientError) Error() string { return e.err.Error() }
+
+func isTransient(err error) bool {
+	_, ok := err.(*transientError)
+	return ok
+}
@@ -0,0 +1,145 @@
+package transport
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestContainsTransientResponseCode(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want bool
+	}{
+		{"transient code", "HIRMG:2:2+3:9050:1:Zu viele Anfragen'", true},
+		{"other transient code", "HIRMG:2:2+3:9210:1:Timeout'", true},
+		{"permanent code", "HIRMG:2:2+3:9800:1:Abgelehnt'", false},
+		{"no code at all", "HIRMG:2:2+0:0:1:Auftrag ausgeführt'", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ContainsTransientResponseCode([]byte(tt.body)); got != tt.want {
+				t.Errorf("ContainsTransientResponseCode(%q) = %v, want %v", tt.body, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHTTPTransport_RetriesOnServerError(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("HIRMG:2:2+0:0:1:Auftrag ausgeführt'"))
+	}))
+	defer server.Close()
+
+	transport := NewHTTPTransport(1000, 1000)
+	transport.BaseBackoff = time.Millisecond
+
+	response, err := transport.Do(context.Background(), server.URL, []byte("request"))
+	if err != nil {
+		t.Fatalf("Do() returned error: %v", err)
+	}
+	if string(response) != "HIRMG:2:2+0:0:1:Auftrag ausgeführt'" {
+		t.Errorf("unexpected response body: %q", response)
+	}
+	if got := atomic.LoadInt32(&requests); got != 3 {
+		t.Errorf("expected 3 requests, got %d", got)
+	}
+}
+
+func TestHTTPTransport_RetriesOnTransientHBCIResponseCode(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) < 2 {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte("HIRMG:2:2+3:9050:1:Zu viele Anfragen'"))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("HIRMG:2:2+0:0:1:Auftrag ausgeführt'"))
+	}))
+	defer server.Close()
+
+	transport := NewHTTPTransport(1000, 1000)
+	transport.BaseBackoff = time.Millisecond
+
+	response, err := transport.Do(context.Background(), server.URL, []byte("request"))
+	if err != nil {
+		t.Fatalf("Do() returned error: %v", err)
+	}
+	if string(response) != "HIRMG:2:2+0:0:1:Auftrag ausgeführt'" {
+		t.Errorf("unexpected response body: %q", response)
+	}
+	if got := atomic.LoadInt32(&requests); got != 2 {
+		t.Errorf("expected 2 requests, got %d", got)
+	}
+}
+
+func TestHTTPTransport_DoesNotRetryClientError(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	transport := NewHTTPTransport(1000, 1000)
+	transport.BaseBackoff = time.Millisecond
+
+	if _, err := transport.Do(context.Background(), server.URL, []byte("request")); err == nil {
+		t.Fatal("expected Do() to return an error for a permanent client error")
+	}
+	if got := atomic.LoadInt32(&requests); got != 1 {
+		t.Errorf("expected exactly 1 request for a non-retryable error, got %d", got)
+	}
+}
+
+func TestHTTPTransport_RespectsRateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	transport := NewHTTPTransport(2, 1)
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if _, err := transport.Do(context.Background(), server.URL, []byte("request")); err != nil {
+			t.Fatalf("Do() returned error: %v", err)
+		}
+	}
+	// With a burst of 1 at 2 requests/second, the 2nd and 3rd requests each
+	// wait for a refill of ~0.5s, so 3 requests take about 1s.
+	if elapsed := time.Since(start); elapsed < 400*time.Millisecond {
+		t.Errorf("expected the rate limiter to slow requests down, took only %s", elapsed)
+	}
+}
+
+func TestHTTPTransport_CtxCancellation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	transport := NewHTTPTransport(0.001, 1)
+	// Use up the single burst token so the next request has to wait for the
+	// rate limiter.
+	if _, err := transport.Do(context.Background(), server.URL, []byte("request")); err != nil {
+		t.Fatalf("Do() returned error: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if _, err := transport.Do(ctx, server.URL, []byte("request")); err == nil {
+		t.Fatal("expected Do() to return an error once ctx is cancelled while waiting for the rate limiter")
+	}
+}
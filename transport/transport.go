@@ -0,0 +1,12 @@
+// Package transport sends HBCI/FinTS messages to a bank's endpoint over a
+// pluggable Transport, decoupling the dialog layer from the concrete
+// network implementation.
+package transport
+
+import "context"
+
+// Transport sends a single HBCI message to addr and returns the raw
+// response body. Implementations must honor ctx cancellation.
+type Transport interface {
+	Do(ctx context.Context, addr string, message []byte) ([]byte, error)
+}